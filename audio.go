@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync/atomic"
+	"time"
+
+	"github.com/hajimehoshi/oto"
+)
+
+const (
+	audioSampleRate     = 44100
+	audioChannels       = 2
+	audioBytesPerSample = 2 // s16le
+)
+
+// AudioPlayer decodes a source's audio track through a dedicated ffmpeg
+// process and plays it back via oto. Its running playback position serves
+// as the pipeline's master clock so video frames can be paced against it
+// instead of a fixed sleep.
+type AudioPlayer struct {
+	cmd          *exec.Cmd
+	context      *oto.Context
+	player       *oto.Player
+	bytesWritten int64
+}
+
+// startAudio spawns an ffmpeg process decoding source's audio track to raw
+// PCM and begins streaming it to the speakers. device, if non-empty, is
+// exported as AUDIODEV so the platform audio backend can select it; offset
+// seeks the audio to match a video seek so the two stay in sync.
+func startAudio(source Source, device string, offset time.Duration) (*AudioPlayer, error) {
+	if device != "" {
+		os.Setenv("AUDIODEV", device)
+	}
+
+	var args []string
+	if offset > 0 {
+		args = append(args, "-ss", formatSeekTime(offset))
+	}
+	args = append(args, source.Args()...)
+	args = append(args,
+		"-vn", "-f", "s16le",
+		"-ar", fmt.Sprintf("%d", audioSampleRate),
+		"-ac", fmt.Sprintf("%d", audioChannels), "-")
+
+	cmd := exec.Command("ffmpeg", args...)
+	cmd.Stdin = source.Stdin()
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("creating audio pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting audio ffmpeg: %w", err)
+	}
+
+	ctx, ready, err := oto.NewContext(audioSampleRate, audioChannels, audioBytesPerSample, 8192)
+	if err != nil {
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("opening audio device: %w", err)
+	}
+	<-ready
+
+	ap := &AudioPlayer{cmd: cmd, context: ctx, player: ctx.NewPlayer()}
+	go ap.pump(stdout)
+	return ap, nil
+}
+
+// pump copies decoded PCM from ffmpeg into the audio player, tracking the
+// number of bytes written so Position can derive elapsed playback time.
+func (a *AudioPlayer) pump(r io.Reader) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			a.player.Write(buf[:n])
+			atomic.AddInt64(&a.bytesWritten, int64(n))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Position returns elapsed audio playback time, used as the master clock
+// that video frame pacing is measured against.
+func (a *AudioPlayer) Position() time.Duration {
+	bytes := atomic.LoadInt64(&a.bytesWritten)
+	frames := bytes / int64(audioChannels*audioBytesPerSample)
+	return time.Duration(frames) * time.Second / audioSampleRate
+}
+
+// Close tears down the audio player, its ffmpeg process, and the output
+// device.
+func (a *AudioPlayer) Close() {
+	a.player.Close()
+	a.context.Close()
+	a.cmd.Process.Kill()
+}
+
+// pace decides, relative to the audio master clock, whether frameCount
+// should be dropped to catch up or how long to hold it before the next
+// frame. When audio is nil it falls back to the original fixed-interval
+// sleep derived from startTime.
+func pace(frameCount int, frameDuration time.Duration, startTime time.Time, audio *AudioPlayer) (drop bool, wait time.Duration) {
+	if audio == nil {
+		elapsed := time.Since(startTime)
+		if elapsed < frameDuration {
+			return false, frameDuration - elapsed
+		}
+		return false, 0
+	}
+
+	targetPTS := time.Duration(frameCount) * frameDuration
+	diff := targetPTS - audio.Position()
+	switch {
+	case diff < -frameDuration:
+		// Renderer has fallen behind the audio clock; drop this frame
+		// to catch back up instead of letting the gap grow.
+		return true, 0
+	case diff > frameDuration:
+		// Renderer is ahead of the audio clock; hold the current frame
+		// on screen until audio catches up.
+		return false, diff
+	default:
+		return false, 0
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	seekStep  = 5 * time.Second
+	speedStep = 0.25
+	minSpeed  = 0.25
+	maxSpeed  = 4.0
+)
+
+// PlaybackState is the shared, concurrency-safe state a keyboard-reader
+// goroutine writes to and the render loop reads from: pause, seek
+// requests, speed, and the FPS overlay toggle.
+type PlaybackState struct {
+	mu      sync.Mutex
+	paused  bool
+	speed   float64
+	showFPS bool
+
+	position int64 // nanoseconds, current playhead; read via atomic
+	targetW  int64 // in-process downscale target, atomic
+	targetH  int64
+
+	seekCh   chan time.Duration
+	resizeCh chan TermSize
+	done     chan struct{}
+}
+
+func newPlaybackState(width, height int) *PlaybackState {
+	return &PlaybackState{
+		speed:    1.0,
+		showFPS:  true,
+		targetW:  int64(width),
+		targetH:  int64(height),
+		seekCh:   make(chan time.Duration, 1),
+		resizeCh: make(chan TermSize, 1),
+		done:     make(chan struct{}),
+	}
+}
+
+func (p *PlaybackState) TogglePause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = !p.paused
+}
+
+func (p *PlaybackState) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+func (p *PlaybackState) ToggleFPS() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.showFPS = !p.showFPS
+}
+
+func (p *PlaybackState) ShowFPS() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.showFPS
+}
+
+func (p *PlaybackState) Speed() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.speed
+}
+
+func (p *PlaybackState) AdjustSpeed(delta float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.speed += delta
+	if p.speed < minSpeed {
+		p.speed = minSpeed
+	}
+	if p.speed > maxSpeed {
+		p.speed = maxSpeed
+	}
+}
+
+func (p *PlaybackState) SetPosition(d time.Duration) {
+	atomic.StoreInt64(&p.position, int64(d))
+}
+
+func (p *PlaybackState) Position() time.Duration {
+	return time.Duration(atomic.LoadInt64(&p.position))
+}
+
+// RequestSeek queues a seek to d, replacing any not-yet-handled seek
+// request so the render loop only ever acts on the latest one.
+func (p *PlaybackState) RequestSeek(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	select {
+	case p.seekCh <- d:
+	default:
+		select {
+		case <-p.seekCh:
+		default:
+		}
+		p.seekCh <- d
+	}
+}
+
+// SetTarget updates the in-process downscale target size.
+func (p *PlaybackState) SetTarget(width, height int) {
+	atomic.StoreInt64(&p.targetW, int64(width))
+	atomic.StoreInt64(&p.targetH, int64(height))
+}
+
+// Target returns the current in-process downscale target size.
+func (p *PlaybackState) Target() (int, int) {
+	return int(atomic.LoadInt64(&p.targetW)), int(atomic.LoadInt64(&p.targetH))
+}
+
+// RequestResize queues a pipeline restart at the new terminal size,
+// replacing any not-yet-handled resize so the render loop only ever acts
+// on the latest one.
+func (p *PlaybackState) RequestResize(size TermSize) {
+	select {
+	case p.resizeCh <- size:
+	default:
+		select {
+		case <-p.resizeCh:
+		default:
+		}
+		p.resizeCh <- size
+	}
+}
+
+func (p *PlaybackState) Quit() {
+	select {
+	case <-p.done:
+	default:
+		close(p.done)
+	}
+}
+
+func (p *PlaybackState) Done() <-chan struct{} {
+	return p.done
+}
+
+// runKeyboard puts the terminal into raw mode and translates keystrokes
+// into PlaybackState changes until Quit is called or stdin closes. The
+// returned restore func must be called to leave raw mode.
+func runKeyboard(state *PlaybackState) (restore func(), err error) {
+	fd := int(os.Stdin.Fd())
+	prev, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("entering raw mode: %w", err)
+	}
+
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			if _, err := os.Stdin.Read(buf); err != nil {
+				return
+			}
+			switch buf[0] {
+			case ' ':
+				state.TogglePause()
+			case 'q':
+				state.Quit()
+				return
+			case 'f':
+				state.ToggleFPS()
+			case 0x1b: // start of an escape sequence (arrow keys)
+				seq := make([]byte, 2)
+				if _, err := os.Stdin.Read(seq); err != nil || seq[0] != '[' {
+					continue
+				}
+				switch seq[1] {
+				case 'C': // right arrow: seek forward
+					state.RequestSeek(state.Position() + seekStep)
+				case 'D': // left arrow: seek backward
+					state.RequestSeek(state.Position() - seekStep)
+				case 'A': // up arrow: speed up
+					state.AdjustSpeed(speedStep)
+				case 'B': // down arrow: slow down
+					state.AdjustSpeed(-speedStep)
+				}
+			}
+		}
+	}()
+
+	return func() { term.Restore(fd, prev) }, nil
+}
+
+// probeDuration asks ffprobe for source's total duration. Network sources
+// and pipes don't have a meaningful duration, so it returns 0 for anything
+// other than a local file.
+func probeDuration(source Source) time.Duration {
+	fs, ok := source.(FileSource)
+	if !ok {
+		return 0
+	}
+
+	out, err := exec.Command("ffprobe", "-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1", fs.Path).Output()
+	if err != nil {
+		return 0
+	}
+
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// formatSeekTime renders d as ffmpeg's -ss argument format.
+func formatSeekTime(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	total := d.Round(time.Millisecond)
+	h := total / time.Hour
+	total -= h * time.Hour
+	m := total / time.Minute
+	total -= m * time.Minute
+	s := total.Seconds()
+	return fmt.Sprintf("%02d:%02d:%06.3f", h, m, s)
+}
+
+// formatDuration renders d as the status-line "mm:ss" / "h:mm:ss" form, or
+// "--:--" when it's unknown.
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "--:--"
+	}
+	d = d.Round(time.Second)
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+	s := d / time.Second
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"sync/atomic"
+)
+
+// fullBytesSampleInterval controls how often Render measures what a full
+// redraw of the current frame would have cost, on top of the full redraws
+// it already does. Composing renderCells on every diffed frame just to
+// throw the result away would defeat most of the CPU savings --diff is
+// for, so the full-redraw byte count is sampled instead of tracked exactly.
+const fullBytesSampleInterval = 30
+
+// DiffRenderer wraps a CellRenderer and emits only the cells that changed
+// since the previous frame, instead of redrawing the whole grid every
+// time. It cuts terminal/network bandwidth substantially on mostly-static
+// content at the cost of tracking the previous frame's cell grid.
+type DiffRenderer struct {
+	inner CellRenderer
+	prev  [][]Cell
+
+	framesRendered int64
+	actualBytes    int64
+	fullBytes      int64
+	fullSamples    int64
+}
+
+// newDiffRenderer wraps inner for differential updates.
+func newDiffRenderer(inner CellRenderer) *DiffRenderer {
+	return &DiffRenderer{inner: inner}
+}
+
+func (d *DiffRenderer) Render(img image.Image) string {
+	cells := d.inner.Cells(img)
+
+	full := d.prev == nil || len(cells) != len(d.prev)
+	if !full {
+		for y, row := range cells {
+			if len(row) != len(d.prev[y]) {
+				full = true
+				break
+			}
+		}
+	}
+
+	frame := atomic.LoadInt64(&d.framesRendered)
+	sampleFull := full || frame%fullBytesSampleInterval == 0
+
+	var out string
+	var rendered string
+	if sampleFull {
+		rendered = renderCells(cells)
+	}
+	if full {
+		out = "\033[H\033[2J" + rendered
+	} else {
+		out = d.renderDiff(cells)
+	}
+	if sampleFull {
+		atomic.AddInt64(&d.fullBytes, int64(len("\033[H"+rendered)))
+		atomic.AddInt64(&d.fullSamples, 1)
+	}
+
+	d.prev = cells
+	atomic.AddInt64(&d.framesRendered, 1)
+	atomic.AddInt64(&d.actualBytes, int64(len(out)))
+	return out
+}
+
+// renderDiff emits only the cells that differ from d.prev, moving the
+// cursor to the start of each changed run and skipping the color escape
+// when it matches the last one emitted within that run.
+func (d *DiffRenderer) renderDiff(cells [][]Cell) string {
+	var builder strings.Builder
+	for y, row := range cells {
+		x := 0
+		for x < len(row) {
+			if row[x] == d.prev[y][x] {
+				x++
+				continue
+			}
+
+			fmt.Fprintf(&builder, "\033[%d;%dH", y+1, x+1)
+			var lastColor [3]uint8
+			haveColor := false
+			for x < len(row) && row[x] != d.prev[y][x] {
+				c := row[x]
+				color := [3]uint8{c.R, c.G, c.B}
+				if !haveColor || color != lastColor {
+					builder.WriteString(rgbToAnsi(c.R, c.G, c.B))
+					lastColor = color
+					haveColor = true
+				}
+				builder.WriteRune(c.Ch)
+				x++
+			}
+			builder.WriteString("\033[0m")
+		}
+	}
+	return builder.String()
+}
+
+// Invalidate forces the next Render to do a full redraw. Callers must
+// call this whenever they clear the screen out-of-band (a resize or a
+// reconnect banner), since the diff otherwise compares against a cell
+// grid the terminal no longer actually shows.
+func (d *DiffRenderer) Invalidate() {
+	d.prev = nil
+}
+
+// Stats reports how many bytes the diff path actually wrote, against the
+// average bytes per frame a full redraw would cost (sampled rather than
+// measured on every frame; see fullBytesSampleInterval).
+func (d *DiffRenderer) Stats() (frames, actualBytes, avgFullBytes int64) {
+	frames = atomic.LoadInt64(&d.framesRendered)
+	actualBytes = atomic.LoadInt64(&d.actualBytes)
+	if samples := atomic.LoadInt64(&d.fullSamples); samples > 0 {
+		avgFullBytes = atomic.LoadInt64(&d.fullBytes) / samples
+	}
+	return frames, actualBytes, avgFullBytes
+}
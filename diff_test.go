@@ -0,0 +1,49 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// syntheticFrame builds a mostly-static width x height RGBA frame with a
+// single moving pixel, standing in for the largely-unchanging video content
+// --diff is meant to help with.
+func syntheticFrame(width, height, frame int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 20, G: 20, B: 20, A: 255})
+		}
+	}
+	img.Set((frame*3)%width, (frame*2)%height, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	return img
+}
+
+// BenchmarkDiffRenderer measures bytes/frame through DiffRenderer against a
+// synthetic mostly-static sequence, the scenario --diff targets.
+func BenchmarkDiffRenderer(b *testing.B) {
+	const width, height = 80, 24
+	diff := newDiffRenderer(RampRenderer{Ramp: rampDefault})
+
+	b.ResetTimer()
+	var total int
+	for i := 0; i < b.N; i++ {
+		total += len(diff.Render(syntheticFrame(width, height, i)))
+	}
+	b.ReportMetric(float64(total)/float64(b.N), "bytes/frame")
+}
+
+// BenchmarkFullRedraw measures bytes/frame for the same sequence rendered
+// without diffing, the baseline --diff is compared against.
+func BenchmarkFullRedraw(b *testing.B) {
+	const width, height = 80, 24
+	renderer := RampRenderer{Ramp: rampDefault}
+
+	b.ResetTimer()
+	var total int
+	for i := 0; i < b.N; i++ {
+		total += len(renderer.Render(syntheticFrame(width, height, i)))
+	}
+	b.ReportMetric(float64(total)/float64(b.N), "bytes/frame")
+}
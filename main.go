@@ -2,20 +2,21 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"image"
 	"image/color"
 	"os"
 	"os/exec"
-	"strings"
 	"time"
 
 	"golang.org/x/term"
 )
 
 const (
-	ASCII_CHARS = " .:-=+*#%@"
-	FPS         = 24
+	FPS = 24
+
+	reconnectMaxBackoff = 10 * time.Second
 )
 
 func getTerminalSize() (int, int) {
@@ -26,39 +27,6 @@ func getTerminalSize() (int, int) {
 	return width, height - 2 // Leave space for status line
 }
 
-func rgbToAnsi(r, g, b uint8) string {
-	return fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b)
-}
-
-func brightnessToASCII(brightness float64) byte {
-	idx := int(brightness * float64(len(ASCII_CHARS)-1))
-	if idx >= len(ASCII_CHARS) {
-		idx = len(ASCII_CHARS) - 1
-	}
-	return ASCII_CHARS[idx]
-}
-
-func frameToASCII(img image.Image) string {
-	var builder strings.Builder
-	bounds := img.Bounds()
-
-	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			c := img.At(x, y)
-			r, g, b, _ := c.RGBA()
-			r8, g8, b8 := uint8(r>>8), uint8(g>>8), uint8(b>>8)
-
-			brightness := (0.299*float64(r8) + 0.587*float64(g8) + 0.114*float64(b8)) / 255.0
-			char := brightnessToASCII(brightness)
-
-			builder.WriteString(rgbToAnsi(r8, g8, b8))
-			builder.WriteByte(char)
-		}
-		builder.WriteString("\033[0m\n")
-	}
-	return builder.String()
-}
-
 func readRawFrame(reader *bufio.Reader, width, height int) (image.Image, error) {
 	img := image.NewRGBA(image.Rect(0, 0, width, height))
 	data := make([]byte, width*height*3)
@@ -82,51 +50,90 @@ func readRawFrame(reader *bufio.Reader, width, height int) (image.Image, error)
 	return img, nil
 }
 
-func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run main.go <video_file>")
-		os.Exit(1)
+// buildFFmpegCmd assembles the ffmpeg invocation that decodes source into
+// the rawvideo rgb24 pipeline the renderer reads from. offset seeks into
+// the source before decoding starts (zero for no seek); fps sets the
+// emitted frame rate. Speed changes are applied on the Go side (see
+// runOnce's frameDuration), not by varying fps here: resampling the same
+// clip to a different fps changes frame count but not wall-clock runtime.
+func buildFFmpegCmd(source Source, width, height int, offset time.Duration, fps int) *exec.Cmd {
+	var args []string
+	if offset > 0 {
+		args = append(args, "-ss", formatSeekTime(offset))
 	}
+	args = append(args, source.Args()...)
+	args = append(args,
+		"-vf", fmt.Sprintf("fps=%d,scale=%d:%d", fps, width, height),
+		"-f", "rawvideo", "-pix_fmt", "rgb24", "-")
 
-	videoPath := os.Args[1]
-	frameDuration := time.Duration(1000000/FPS) * time.Microsecond
+	cmd := exec.Command("ffmpeg", args...)
+	cmd.Stdin = source.Stdin()
+	return cmd
+}
 
-	// Get terminal size automatically
-	WIDTH, HEIGHT := getTerminalSize()
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(os.Args[2:])
+		return
+	}
 
-	cmd := exec.Command("ffmpeg", "-i", videoPath, 
-		"-vf", fmt.Sprintf("fps=%d,scale=%d:%d", FPS, WIDTH, HEIGHT),
-		"-f", "rawvideo", "-pix_fmt", "rgb24", "-")
+	rtspFlag := flag.String("rtsp", "", "RTSP stream URL (rtsp://...)")
+	urlFlag := flag.String("url", "", "HTTP(S) video URL")
+	pipeFlag := flag.Bool("pipe", false, "read a video stream from stdin")
+	noAudioFlag := flag.Bool("no-audio", false, "disable audio playback")
+	audioDeviceFlag := flag.String("audio-device", "", "audio output device (exported as AUDIODEV)")
+	rendererFlag := flag.String("renderer", "ramp", "frame renderer: ramp, dither, braille, or halfblock")
+	rampFlag := flag.String("ramp", "default", "character ramp: default, short, long, or a custom string")
+	invertFlag := flag.Bool("invert", false, "invert brightness, for light-on-dark terminals")
+	resizeFlag := flag.String("resize-strategy", "restart", "terminal resize handling: restart or inprocess")
+	diffFlag := flag.Bool("diff", false, "only redraw cells that changed since the last frame")
+	flag.Parse()
+
+	var positional string
+	if flag.NArg() > 0 {
+		positional = flag.Arg(0)
+	}
 
-	stdout, err := cmd.StdoutPipe()
+	source, err := parseSource(*rtspFlag, *urlFlag, *pipeFlag, positional)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating pipe: %v\n", err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, "Usage: monoscope <video_file> | --rtsp <url> | --url <url> | --pipe | serve -stream name=source")
 		os.Exit(1)
 	}
 
-	stderr, err := cmd.StderrPipe()
+	renderer, err := newRenderer(*rendererFlag, *rampFlag, *invertFlag)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating stderr pipe: %v\n", err)
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
-	if err := cmd.Start(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error starting ffmpeg: %v\n", err)
-		fmt.Fprintf(os.Stderr, "Make sure ffmpeg is installed and the video file exists\n")
+	if *diffFlag {
+		if cr, ok := renderer.(CellRenderer); ok {
+			renderer = newDiffRenderer(cr)
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: --diff isn't supported by renderer %q, ignoring\n", *rendererFlag)
+		}
+	}
+
+	resizeStrategy, err := parseResizeStrategy(*resizeFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
-	defer cmd.Process.Kill()
 
-	// Discard FFmpeg stderr output in background
-	go func() {
-		buf := make([]byte, 4096)
-		for {
-			stderr.Read(buf)
-		}
-	}()
+	// Get terminal size automatically
+	WIDTH, HEIGHT := getTerminalSize()
+
+	player := &Player{
+		Source:         source,
+		Width:          WIDTH,
+		Height:         HEIGHT,
+		Renderer:       renderer,
+		AudioOn:        !*noAudioFlag,
+		AudioDevice:    *audioDeviceFlag,
+		ResizeStrategy: resizeStrategy,
+	}
 
-	reader := bufio.NewReader(stdout)
-	
 	// Enter alternate screen buffer and hide cursor
 	fmt.Print("\033[?1049h\033[?25l")
 	defer func() {
@@ -134,38 +141,25 @@ func main() {
 		fmt.Println()
 	}()
 
-	frameCount := 0
-	// THE ONE LOOP - reads video frames, converts to ASCII, displays
-	for {
-		startTime := time.Now()
-
-		img, err := readRawFrame(reader, WIDTH, HEIGHT)
-		if err != nil {
-			if frameCount == 0 {
-				fmt.Print("\033[?1049l\033[?25h")
-				fmt.Fprintf(os.Stderr, "Error: Could not read any frames. Check if video file is valid.\n")
-				os.Exit(1)
-			}
-			break
-		}
+	frameCount, err := player.Run()
+	if err != nil && frameCount == 0 {
+		fmt.Print("\033[?1049l\033[?25h")
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-		asciiFrame := frameToASCII(img)
-		
-		// Move cursor to home position and print frame
-		fmt.Print("\033[H" + asciiFrame)
-		fmt.Printf("\033[0mFrame: %d | FPS: %d | Press Ctrl+C to exit", frameCount, FPS)
-		
-		frameCount++
-
-		elapsed := time.Since(startTime)
-		if elapsed < frameDuration {
-			time.Sleep(frameDuration - elapsed)
+	fmt.Print("\033[H\033[2J\033[32m")
+	fmt.Printf("Video complete! %d frames played.\n", frameCount)
+	if d, ok := renderer.(*DiffRenderer); ok {
+		frames, actual, avgFull := d.Stats()
+		if frames > 0 && avgFull > 0 {
+			actualPerFrame := float64(actual) / float64(frames)
+			fmt.Printf("Diff rendering: %.0f bytes/frame vs %.0f bytes/frame full redraw (%.1f%% reduction)\n",
+				actualPerFrame, float64(avgFull), 100*(1-actualPerFrame/float64(avgFull)))
 		}
 	}
+	fmt.Print("Press Enter to exit...\033[0m")
 
-	fmt.Print("\033[H\033[2J\033[32m")
-	fmt.Printf("Video complete! %d frames played.\nPress Enter to exit...\033[0m", frameCount)
-	
 	// Wait for user input before exiting
 	fmt.Scanln()
 }
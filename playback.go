@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Player drives the full interactive playback experience for a single
+// Source: the ffmpeg pipeline, the renderer, the optional audio clock,
+// and keyboard-driven pause/seek/speed control.
+type Player struct {
+	Source         Source
+	Width          int
+	Height         int
+	Renderer       Renderer
+	AudioOn        bool
+	AudioDevice    string
+	ResizeStrategy ResizeStrategy
+}
+
+// runResult is what one ffmpeg pipeline attempt produced: the frame
+// count it reached, and a pending seek or resize if the user requested
+// one (or the terminal was resized) while it was running.
+type runResult struct {
+	frameCount int
+	seek       *time.Duration
+	resize     *TermSize
+}
+
+// Run plays Source until the user quits or it reaches the end, handling
+// reconnects, seeks, and terminal resizes (under ResizeRestart) by
+// restarting the ffmpeg pipeline as needed; speed changes take effect per
+// frame without a restart. It returns the number of frames rendered.
+func (p *Player) Run() (int, error) {
+	duration := probeDuration(p.Source)
+	state := newPlaybackState(p.Width, p.Height)
+
+	restore, err := runKeyboard(state)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: interactive controls disabled: %v\n", err)
+	} else {
+		defer restore()
+	}
+
+	resizes := watchResize()
+	go func() {
+		for size := range resizes {
+			if p.ResizeStrategy == ResizeInProcess {
+				state.SetTarget(size.Width, size.Height)
+				fmt.Print("\033[2J")
+				p.invalidateDiff()
+			} else {
+				state.RequestResize(size)
+			}
+		}
+	}()
+
+	frameCount := 0
+	offset := time.Duration(0)
+	retry := newBackoff(reconnectMaxBackoff)
+
+	for {
+		select {
+		case <-state.Done():
+			return frameCount, nil
+		default:
+		}
+
+		var audio *AudioPlayer
+		if p.AudioOn {
+			if _, isStdin := p.Source.(StdinSource); isStdin {
+				fmt.Fprintln(os.Stderr, "Warning: audio disabled, stdin can't feed two ffmpeg processes")
+			} else if a, aerr := startAudio(p.Source, p.AudioDevice, offset); aerr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: audio disabled: %v\n", aerr)
+			} else {
+				audio = a
+			}
+		}
+
+		before := frameCount
+		result, runErr := p.runOnce(state, offset, frameCount, duration, audio)
+		frameCount = result.frameCount
+		if audio != nil {
+			audio.Close()
+		}
+
+		if result.seek != nil {
+			offset = *result.seek
+			continue
+		}
+		if result.resize != nil {
+			offset = state.Position()
+			p.Width, p.Height = result.resize.Width, result.resize.Height
+			state.SetTarget(p.Width, p.Height)
+			fmt.Print("\033[2J")
+			p.invalidateDiff()
+			continue
+		}
+		if runErr == nil || !p.Source.Reconnectable() {
+			return frameCount, runErr
+		}
+
+		if frameCount > before {
+			retry.reset()
+		}
+
+		// Network source dropped mid-stream: reconnect from where
+		// playback actually left off, not the last explicit seek, and
+		// retry with backoff instead of ending playback.
+		offset = state.Position()
+		wait := retry.next()
+		fmt.Printf("\033[H\033[2J\033[33mSource dropped (%v), reconnecting in %s...\033[0m", runErr, wait)
+		p.invalidateDiff()
+		time.Sleep(wait)
+	}
+}
+
+// invalidateDiff forces the next frame to be a full redraw if p.Renderer
+// is a DiffRenderer, matching a screen clear the caller just printed.
+func (p *Player) invalidateDiff() {
+	if d, ok := p.Renderer.(*DiffRenderer); ok {
+		d.Invalidate()
+	}
+}
+
+// runOnce starts a single ffmpeg pipeline at offset and renders frames
+// until the source ends, errors out, or a seek, resize, or quit is
+// requested. While paused it waits without reading frames.
+func (p *Player) runOnce(state *PlaybackState, offset time.Duration, frameCount int, duration time.Duration, audio *AudioPlayer) (runResult, error) {
+	fps := FPS
+
+	decodeWidth, decodeHeight := p.Width, p.Height
+	if p.ResizeStrategy == ResizeInProcess {
+		decodeWidth, decodeHeight = inProcessDecodeWidth, inProcessDecodeHeight
+	}
+
+	cmd := buildFFmpegCmd(p.Source, decodeWidth, decodeHeight, offset, fps)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return runResult{frameCount, nil, nil}, fmt.Errorf("creating pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return runResult{frameCount, nil, nil}, fmt.Errorf("creating stderr pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return runResult{frameCount, nil, nil}, fmt.Errorf("starting ffmpeg: %w", err)
+	}
+	defer cmd.Process.Kill()
+
+	// Discard FFmpeg stderr output in background
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			if _, err := stderr.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	reader := bufio.NewReader(stdout)
+	startFrame := frameCount
+	position := offset
+	// Each decoded frame covers 1/fps of media time whatever the playback
+	// speed, so the playhead advances by that rather than by frameDuration.
+	mediaStep := time.Second / time.Duration(fps)
+
+	for {
+		select {
+		case d := <-state.seekCh:
+			return runResult{frameCount, &d, nil}, nil
+		case size := <-state.resizeCh:
+			return runResult{frameCount, nil, &size}, nil
+		case <-state.Done():
+			return runResult{frameCount, nil, nil}, nil
+		default:
+		}
+
+		if state.Paused() {
+			select {
+			case d := <-state.seekCh:
+				return runResult{frameCount, &d, nil}, nil
+			case size := <-state.resizeCh:
+				return runResult{frameCount, nil, &size}, nil
+			case <-state.Done():
+				return runResult{frameCount, nil, nil}, nil
+			case <-time.After(100 * time.Millisecond):
+			}
+			continue
+		}
+
+		startTime := time.Now()
+
+		img, err := readRawFrame(reader, decodeWidth, decodeHeight)
+		if err != nil {
+			if frameCount == startFrame {
+				return runResult{frameCount, nil, nil}, fmt.Errorf("could not read any frames from %s: %w", p.Source, err)
+			}
+			return runResult{frameCount, nil, nil}, err
+		}
+
+		if p.ResizeStrategy == ResizeInProcess {
+			targetW, targetH := state.Target()
+			img = downscale(img, targetW, targetH)
+		}
+
+		// Read speed fresh every frame so ↑/↓ takes effect immediately
+		// instead of only on the next pipeline restart.
+		speed := state.Speed()
+		frameDuration := time.Duration(float64(time.Second/time.Duration(fps)) / speed)
+
+		state.SetPosition(position)
+
+		// The audio master clock only tracks native (1x) playback; at any
+		// other speed it would just fight the sleep-based pacer and pull
+		// video back to 1x, so fall back to plain sleep pacing instead.
+		clockAudio := audio
+		if speed != 1.0 {
+			clockAudio = nil
+		}
+		drop, wait := pace(frameCount-startFrame, frameDuration, startTime, clockAudio)
+		if drop {
+			frameCount++
+			position += mediaStep
+			continue
+		}
+
+		frame := p.Renderer.Render(img)
+		if d, ok := p.Renderer.(*DiffRenderer); ok {
+			// A diff leaves the cursor after the last changed cell (or at
+			// home when nothing changed); move it below the frame.
+			frame += fmt.Sprintf("\033[%d;1H", len(d.prev)+1)
+		}
+		fmt.Print("\033[H" + frame)
+		fmt.Print(statusLine(fps, p.Source, state, position, duration))
+		frameCount++
+		position += mediaStep
+
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+}
+
+// statusLine renders the line shown below each frame: position/duration,
+// speed, pause state, and the keybindings reminder.
+func statusLine(fps int, source Source, state *PlaybackState, position, duration time.Duration) string {
+	var b strings.Builder
+	b.WriteString("\033[0m")
+	fmt.Fprintf(&b, "%s / %s", formatDuration(position), formatDuration(duration))
+	fmt.Fprintf(&b, " | Speed: %.2fx", state.Speed())
+	if state.Paused() {
+		b.WriteString(" | PAUSED")
+	}
+	if state.ShowFPS() {
+		fmt.Fprintf(&b, " | FPS: %d", fps)
+	}
+	fmt.Fprintf(&b, " | %s", source)
+	b.WriteString(" | space pause, ←/→ seek, ↑/↓ speed, f fps, q quit")
+	return b.String()
+}
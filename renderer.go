@@ -0,0 +1,322 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	rampDefault = " .:-=+*#%@"
+	rampShort   = " .:#@"
+	rampLong    = " .'`^\",:;Il!i><~+_-?][}{1)(|\\/tfjrxnuvczXYUJCLQ0OZmwqpdbkhao*#MW&8%B@$"
+)
+
+// Renderer turns a decoded video frame into the text written to the
+// terminal (or streamed to an HTTP client).
+type Renderer interface {
+	Render(img image.Image) string
+}
+
+// Cell is one character cell's worth of output: a single glyph with a
+// single foreground color.
+type Cell struct {
+	Ch      rune
+	R, G, B uint8
+}
+
+// CellRenderer is a Renderer that can also expose its frame as a cell
+// grid instead of a composed string, which DiffRenderer needs to tell
+// which cells actually changed between frames.
+type CellRenderer interface {
+	Renderer
+	Cells(img image.Image) [][]Cell
+}
+
+// renderCells composes a cell grid into the same "full redraw" ANSI text
+// every CellRenderer's Render produces: one escape+glyph per cell, a
+// reset at the end of each row.
+func renderCells(cells [][]Cell) string {
+	var builder strings.Builder
+	for _, row := range cells {
+		for _, c := range row {
+			builder.WriteString(rgbToAnsi(c.R, c.G, c.B))
+			builder.WriteRune(c.Ch)
+		}
+		builder.WriteString("\033[0m\n")
+	}
+	return builder.String()
+}
+
+func rgbToAnsi(r, g, b uint8) string {
+	return fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b)
+}
+
+func luminance(r, g, b uint8) float64 {
+	return (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 255.0
+}
+
+// resolveRamp maps a --ramp flag value to a character ramp, treating
+// anything other than the built-in names as a literal custom ramp.
+func resolveRamp(name string) string {
+	switch name {
+	case "", "default":
+		return rampDefault
+	case "short":
+		return rampShort
+	case "long":
+		return rampLong
+	default:
+		return name
+	}
+}
+
+// validateRamp rejects ramps too short for RampRenderer/DitherRenderer's
+// brightness-to-index math to work: with fewer than 2 characters, the
+// index range collapses to a single level and dividing by it (in the
+// dither error-diffusion step) panics or produces NaN.
+func validateRamp(ramp string) error {
+	if utf8.RuneCountInString(ramp) < 2 {
+		return fmt.Errorf("ramp %q is too short: need at least 2 characters", ramp)
+	}
+	return nil
+}
+
+// newRenderer builds the Renderer selected by --renderer, using ramp and
+// invert where the implementation supports them.
+func newRenderer(kind, rampName string, invert bool) (Renderer, error) {
+	ramp := resolveRamp(rampName)
+	switch kind {
+	case "", "ramp":
+		if err := validateRamp(ramp); err != nil {
+			return nil, err
+		}
+		return RampRenderer{Ramp: ramp, Invert: invert}, nil
+	case "dither":
+		if err := validateRamp(ramp); err != nil {
+			return nil, err
+		}
+		return DitherRenderer{Ramp: ramp, Invert: invert}, nil
+	case "braille":
+		return BrailleRenderer{Threshold: 0.5, Invert: invert}, nil
+	case "halfblock":
+		return HalfBlockRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown renderer %q (want ramp, dither, braille, or halfblock)", kind)
+	}
+}
+
+// RampRenderer maps each pixel's luminance onto a fixed ASCII ramp, one
+// character per cell. This is the original monoscope renderer.
+type RampRenderer struct {
+	Ramp   string
+	Invert bool
+}
+
+// char maps brightness onto a character in runes, ramp's decoded code
+// points. Indexing by rune rather than by byte is what lets Ramp hold
+// multi-byte characters (e.g. "░▒▓█") instead of just ASCII.
+func (r RampRenderer) char(brightness float64, runes []rune) rune {
+	if r.Invert {
+		brightness = 1 - brightness
+	}
+	idx := int(brightness * float64(len(runes)-1))
+	if idx >= len(runes) {
+		idx = len(runes) - 1
+	}
+	return runes[idx]
+}
+
+func (r RampRenderer) Render(img image.Image) string {
+	return renderCells(r.Cells(img))
+}
+
+func (r RampRenderer) Cells(img image.Image) [][]Cell {
+	runes := []rune(r.Ramp)
+	bounds := img.Bounds()
+	cells := make([][]Cell, bounds.Dy())
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := make([]Cell, bounds.Dx())
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := img.At(x, y)
+			rr, gg, bb, _ := c.RGBA()
+			r8, g8, b8 := uint8(rr>>8), uint8(gg>>8), uint8(bb>>8)
+			row[x-bounds.Min.X] = Cell{Ch: r.char(luminance(r8, g8, b8), runes), R: r8, G: g8, B: b8}
+		}
+		cells[y-bounds.Min.Y] = row
+	}
+	return cells
+}
+
+// DitherRenderer quantizes luminance to Ramp using Floyd-Steinberg
+// error diffusion instead of nearest-level rounding, trading sharp
+// banding for finer perceived gradients.
+type DitherRenderer struct {
+	Ramp   string
+	Invert bool
+}
+
+func (r DitherRenderer) Render(img image.Image) string {
+	return renderCells(r.Cells(img))
+}
+
+func (r DitherRenderer) Cells(img image.Image) [][]Cell {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	lum := make([][]float64, h)
+	rgb := make([][][3]uint8, h)
+	for y := 0; y < h; y++ {
+		lum[y] = make([]float64, w)
+		rgb[y] = make([][3]uint8, w)
+		for x := 0; x < w; x++ {
+			c := img.At(bounds.Min.X+x, bounds.Min.Y+y)
+			rr, gg, bb, _ := c.RGBA()
+			r8, g8, b8 := uint8(rr>>8), uint8(gg>>8), uint8(bb>>8)
+			rgb[y][x] = [3]uint8{r8, g8, b8}
+
+			brightness := luminance(r8, g8, b8)
+			if r.Invert {
+				brightness = 1 - brightness
+			}
+			lum[y][x] = brightness
+		}
+	}
+
+	runes := []rune(r.Ramp)
+	levels := float64(len(runes) - 1)
+	cells := make([][]Cell, h)
+	for y := 0; y < h; y++ {
+		row := make([]Cell, w)
+		for x := 0; x < w; x++ {
+			old := clamp01(lum[y][x])
+			idx := int(old*levels + 0.5)
+			if idx > len(runes)-1 {
+				idx = len(runes) - 1
+			}
+			quantErr := old - float64(idx)/levels
+
+			// Floyd-Steinberg: propagate the quantization error to the
+			// unvisited neighbors, walking left-to-right per row.
+			if x+1 < w {
+				lum[y][x+1] += quantErr * 7.0 / 16.0
+			}
+			if y+1 < h {
+				if x-1 >= 0 {
+					lum[y+1][x-1] += quantErr * 3.0 / 16.0
+				}
+				lum[y+1][x] += quantErr * 5.0 / 16.0
+				if x+1 < w {
+					lum[y+1][x+1] += quantErr * 1.0 / 16.0
+				}
+			}
+
+			col := rgb[y][x]
+			row[x] = Cell{Ch: runes[idx], R: col[0], G: col[1], B: col[2]}
+		}
+		cells[y] = row
+	}
+	return cells
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// brailleBit maps a (row, col) offset within a 2x4 cell, indexed as
+// brailleBit[row][col], to the Braille dot bit it sets, per the standard
+// U+2800 dot numbering.
+var brailleBit = [4][2]uint{
+	{0, 3},
+	{1, 4},
+	{2, 5},
+	{6, 7},
+}
+
+// BrailleRenderer packs a 2x4 block of thresholded pixels into a single
+// Braille character (U+2800-U+28FF): two pixels across and four down per
+// cell, against one pixel per cell for a ramp.
+type BrailleRenderer struct {
+	Threshold float64
+	Invert    bool
+}
+
+func (r BrailleRenderer) Render(img image.Image) string {
+	return renderCells(r.Cells(img))
+}
+
+func (r BrailleRenderer) Cells(img image.Image) [][]Cell {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	var cells [][]Cell
+
+	for cellY := 0; cellY < h; cellY += 4 {
+		var row []Cell
+		for cellX := 0; cellX < w; cellX += 2 {
+			var bits uint
+			var rSum, gSum, bSum, n int
+
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					x, y := cellX+dx, cellY+dy
+					if x >= w || y >= h {
+						continue
+					}
+					c := img.At(bounds.Min.X+x, bounds.Min.Y+y)
+					rr, gg, bb, _ := c.RGBA()
+					r8, g8, b8 := uint8(rr>>8), uint8(gg>>8), uint8(bb>>8)
+					rSum, gSum, bSum, n = rSum+int(r8), gSum+int(g8), bSum+int(b8), n+1
+
+					brightness := luminance(r8, g8, b8)
+					if r.Invert {
+						brightness = 1 - brightness
+					}
+					if brightness > r.Threshold {
+						bits |= 1 << brailleBit[dy][dx]
+					}
+				}
+			}
+			if n == 0 {
+				continue
+			}
+
+			row = append(row, Cell{Ch: rune(0x2800 + bits), R: uint8(rSum / n), G: uint8(gSum / n), B: uint8(bSum / n)})
+		}
+		cells = append(cells, row)
+	}
+	return cells
+}
+
+// HalfBlockRenderer draws each output row from two source rows, using
+// '▀' with an independent foreground (top pixel) and background (bottom
+// pixel) color to double vertical resolution.
+type HalfBlockRenderer struct{}
+
+func (r HalfBlockRenderer) Render(img image.Image) string {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	var builder strings.Builder
+
+	for y := 0; y < h; y += 2 {
+		for x := 0; x < w; x++ {
+			tr, tg, tb, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			builder.WriteString(fmt.Sprintf("\033[38;2;%d;%d;%dm", uint8(tr>>8), uint8(tg>>8), uint8(tb>>8)))
+
+			if y+1 < h {
+				br, bg, bb, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y+1).RGBA()
+				builder.WriteString(fmt.Sprintf("\033[48;2;%d;%d;%dm", uint8(br>>8), uint8(bg>>8), uint8(bb>>8)))
+			}
+			builder.WriteRune('▀')
+		}
+		builder.WriteString("\033[0m\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/image/draw"
+)
+
+// ResizeStrategy controls how the Player reacts to a SIGWINCH.
+type ResizeStrategy int
+
+const (
+	// ResizeRestart restarts the ffmpeg pipeline with new scale
+	// parameters at the current playback position. Simple and exact,
+	// but costs a brief stall while ffmpeg spins back up.
+	ResizeRestart ResizeStrategy = iota
+	// ResizeInProcess decodes at a fixed, generously large frame size
+	// once and downscales each frame in-process to the current
+	// terminal size. Avoids ffmpeg restarts entirely, at the cost of
+	// decoding more pixels than the terminal can show.
+	ResizeInProcess
+)
+
+// inProcessDecodeWidth x inProcessDecodeHeight is the fixed frame size requested from ffmpeg when
+// running under ResizeInProcess; it comfortably covers common terminal
+// dimensions so most resizes only need downscaling, not upscaling.
+const (
+	inProcessDecodeWidth  = 320
+	inProcessDecodeHeight = 120
+)
+
+func parseResizeStrategy(name string) (ResizeStrategy, error) {
+	switch name {
+	case "", "restart":
+		return ResizeRestart, nil
+	case "inprocess":
+		return ResizeInProcess, nil
+	default:
+		return 0, fmt.Errorf("unknown resize strategy %q (want restart or inprocess)", name)
+	}
+}
+
+// TermSize is a terminal's dimensions in character cells.
+type TermSize struct {
+	Width, Height int
+}
+
+// watchResize installs a SIGWINCH handler and emits the new terminal size
+// on the returned channel each time the window is resized.
+func watchResize() <-chan TermSize {
+	ch := make(chan TermSize, 1)
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGWINCH)
+
+	go func() {
+		for range sig {
+			w, h := getTerminalSize()
+			select {
+			case ch <- TermSize{w, h}:
+			default:
+				// Previous resize not yet consumed; drop it, the
+				// newer size supersedes it anyway.
+				select {
+				case <-ch:
+				default:
+				}
+				ch <- TermSize{w, h}
+			}
+		}
+	}()
+	return ch
+}
+
+// downscale resamples src down to width x height. x/image/draw has no
+// true Lanczos kernel; CatmullRom is its highest-quality scaler and is
+// used here in its place.
+func downscale(src image.Image, width, height int) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+	return dst
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	serveWidth  = 160
+	serveHeight = 48
+)
+
+// Broadcaster fans the frames from a single ffmpeg+ASCII pipeline out to
+// any number of HTTP subscribers, so adding viewers never spawns another
+// ffmpeg process.
+type Broadcaster struct {
+	mu   sync.Mutex
+	subs map[chan string]struct{}
+}
+
+func newBroadcaster() *Broadcaster {
+	return &Broadcaster{subs: make(map[chan string]struct{})}
+}
+
+func (b *Broadcaster) subscribe() chan string {
+	ch := make(chan string, 4)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *Broadcaster) unsubscribe(ch chan string) {
+	b.mu.Lock()
+	delete(b.subs, ch)
+	b.mu.Unlock()
+	close(ch)
+}
+
+// publish fans frame out to every subscriber, dropping it for any
+// subscriber whose channel is full rather than blocking the pipeline on a
+// slow client.
+func (b *Broadcaster) publish(frame string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- frame:
+		default:
+		}
+	}
+}
+
+// Stream is one named ffmpeg+ASCII pipeline, shared by every HTTP client
+// watching it.
+type Stream struct {
+	Name        string
+	Source      Source
+	Renderer    Renderer
+	broadcaster *Broadcaster
+}
+
+func newStream(name string, source Source, renderer Renderer) *Stream {
+	return &Stream{Name: name, Source: source, Renderer: renderer, broadcaster: newBroadcaster()}
+}
+
+// run decodes Source and publishes ANSI frames to subscribers until ctx is
+// canceled, reconnecting reconnectable sources with backoff exactly like
+// the standalone player does.
+func (s *Stream) run(ctx context.Context, width, height int) {
+	retry := newBackoff(reconnectMaxBackoff)
+	frameCount := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		before := frameCount
+		var err error
+		frameCount, err = s.runOnce(ctx, width, height, frameCount)
+		if err == nil || !s.Source.Reconnectable() {
+			return
+		}
+
+		if frameCount > before {
+			retry.reset()
+		}
+		time.Sleep(retry.next())
+	}
+}
+
+// runOnce runs a single ffmpeg pipeline attempt, publishing each decoded
+// frame to the stream's subscribers until the source ends or errors.
+func (s *Stream) runOnce(ctx context.Context, width, height, frameCount int) (int, error) {
+	frameDuration := time.Duration(1000000/FPS) * time.Microsecond
+
+	cmd := buildFFmpegCmd(s.Source, width, height, 0, FPS)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return frameCount, fmt.Errorf("creating pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return frameCount, fmt.Errorf("creating stderr pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return frameCount, fmt.Errorf("starting ffmpeg: %w", err)
+	}
+	defer cmd.Process.Kill()
+
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			if _, err := stderr.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	reader := bufio.NewReader(stdout)
+	startFrame := frameCount
+	for {
+		if ctx.Err() != nil {
+			return frameCount, ctx.Err()
+		}
+
+		startTime := time.Now()
+
+		img, err := readRawFrame(reader, width, height)
+		if err != nil {
+			if frameCount == startFrame {
+				return frameCount, fmt.Errorf("could not read any frames from %s: %w", s.Source, err)
+			}
+			return frameCount, err
+		}
+
+		s.broadcaster.publish("\033[H" + s.Renderer.Render(img))
+		frameCount++
+
+		elapsed := time.Since(startTime)
+		if elapsed < frameDuration {
+			time.Sleep(frameDuration - elapsed)
+		}
+	}
+}
+
+// ServeHTTP streams this stream's ANSI frames to w as a chunked
+// text/plain response until the client disconnects.
+func (s *Stream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := s.broadcaster.subscribe()
+	defer s.broadcaster.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case frame, ok := <-ch:
+			if !ok {
+				return
+			}
+			if _, err := io.WriteString(w, frame); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// Server holds the set of named streams a `monoscope serve` process is
+// running and exposes them over HTTP.
+type Server struct {
+	mu      sync.RWMutex
+	streams map[string]*Stream
+}
+
+func newServer() *Server {
+	return &Server{streams: make(map[string]*Stream)}
+}
+
+func (srv *Server) add(s *Stream) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.streams[s.Name] = s
+}
+
+func (srv *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/stream/")
+	srv.mu.RLock()
+	s, ok := srv.streams[name]
+	srv.mu.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	s.ServeHTTP(w, r)
+}
+
+// handleDiscovery lists the names of currently active streams as JSON.
+func (srv *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	srv.mu.RLock()
+	names := make([]string, 0, len(srv.streams))
+	for name := range srv.streams {
+		names = append(names, name)
+	}
+	srv.mu.RUnlock()
+
+	sort.Strings(names)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(names)
+}
+
+// streamFlags collects repeated -stream name=source flags.
+type streamFlags []string
+
+func (f *streamFlags) String() string     { return strings.Join(*f, ",") }
+func (f *streamFlags) Set(v string) error { *f = append(*f, v); return nil }
+
+// parseStreamSpec turns a "name=source" flag value into a named Source,
+// picking the Source implementation from the source's URL scheme.
+func parseStreamSpec(spec string) (string, Source, error) {
+	name, raw, ok := strings.Cut(spec, "=")
+	if !ok || name == "" || raw == "" {
+		return "", nil, fmt.Errorf("invalid -stream %q, expected name=source", spec)
+	}
+
+	switch {
+	case strings.HasPrefix(raw, "rtsp://"):
+		return name, RTSPSource{URL: raw}, nil
+	case strings.HasPrefix(raw, "http://"), strings.HasPrefix(raw, "https://"):
+		return name, URLSource{URL: raw}, nil
+	default:
+		return name, FileSource{Path: raw}, nil
+	}
+}
+
+// runServe implements `monoscope serve`: it starts one ffmpeg+ASCII
+// pipeline per -stream flag and serves each over HTTP at /stream/<name>,
+// fanning frames out to however many clients connect.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	rendererFlag := fs.String("renderer", "ramp", "frame renderer: ramp, dither, braille, or halfblock")
+	rampFlag := fs.String("ramp", "default", "character ramp: default, short, long, or a custom string")
+	invertFlag := fs.Bool("invert", false, "invert brightness, for light-on-dark terminals")
+	var specs streamFlags
+	fs.Var(&specs, "stream", "name=source, repeatable (source is a file path, http(s) URL, or rtsp:// URL)")
+	fs.Parse(args)
+
+	if len(specs) == 0 {
+		fmt.Println("Usage: monoscope serve -stream name=source [-stream name2=source2 ...] [-addr :8080]")
+		return
+	}
+
+	renderer, err := newRenderer(*rendererFlag, *rampFlag, *invertFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	srv := newServer()
+	ctx := context.Background()
+	for _, spec := range specs {
+		name, source, err := parseStreamSpec(spec)
+		if err != nil {
+			log.Fatal(err)
+		}
+		stream := newStream(name, source, renderer)
+		srv.add(stream)
+		go stream.run(ctx, serveWidth, serveHeight)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/streams", srv.handleDiscovery)
+	mux.HandleFunc("/stream/", srv.handleStream)
+
+	log.Printf("monoscope serve listening on %s (streams: %s)", *addr, strings.Join(specs, ", "))
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Source describes where ffmpeg should read video from and how the
+// pipeline should react when that input drops.
+type Source interface {
+	// Args returns the ffmpeg arguments that select the input, e.g.
+	// []string{"-i", path} or []string{"-rtsp_transport", "tcp", "-i", url}.
+	Args() []string
+	// Stdin returns the reader ffmpeg's stdin should be wired to, or nil
+	// if the source doesn't need it.
+	Stdin() *os.File
+	// Reconnectable reports whether a dropped connection should be
+	// retried with backoff instead of ending playback.
+	Reconnectable() bool
+	// String is used in status lines and error messages.
+	String() string
+}
+
+// FileSource reads from a local video file on disk.
+type FileSource struct {
+	Path string
+}
+
+func (s FileSource) Args() []string      { return []string{"-i", s.Path} }
+func (s FileSource) Stdin() *os.File     { return nil }
+func (s FileSource) Reconnectable() bool { return false }
+func (s FileSource) String() string      { return s.Path }
+
+// URLSource reads from an HTTP(S) URL.
+type URLSource struct {
+	URL string
+}
+
+func (s URLSource) Args() []string      { return []string{"-i", s.URL} }
+func (s URLSource) Stdin() *os.File     { return nil }
+func (s URLSource) Reconnectable() bool { return true }
+func (s URLSource) String() string      { return s.URL }
+
+// RTSPSource reads from an RTSP camera/stream over TCP.
+type RTSPSource struct {
+	URL string
+}
+
+func (s RTSPSource) Args() []string {
+	return []string{"-rtsp_transport", "tcp", "-i", s.URL}
+}
+func (s RTSPSource) Stdin() *os.File     { return nil }
+func (s RTSPSource) Reconnectable() bool { return true }
+func (s RTSPSource) String() string      { return s.URL }
+
+// StdinSource reads a video stream piped into monoscope's own stdin;
+// ffmpeg detects its container format.
+type StdinSource struct{}
+
+func (s StdinSource) Args() []string      { return []string{"-i", "pipe:0"} }
+func (s StdinSource) Stdin() *os.File     { return os.Stdin }
+func (s StdinSource) Reconnectable() bool { return false }
+func (s StdinSource) String() string      { return "stdin" }
+
+// parseSource picks the Source selected by flags, falling back to the
+// positional video file argument for backwards compatibility.
+func parseSource(rtsp, url string, pipe bool, positional string) (Source, error) {
+	switch {
+	case rtsp != "":
+		return RTSPSource{URL: rtsp}, nil
+	case url != "":
+		return URLSource{URL: url}, nil
+	case pipe:
+		return StdinSource{}, nil
+	case positional != "":
+		return FileSource{Path: positional}, nil
+	default:
+		return nil, fmt.Errorf("no input given: pass a video file, --rtsp, --url, or --pipe")
+	}
+}
+
+// backoff yields increasing retry delays for reconnecting to a dropped
+// network source, capping out so we don't wait forever between attempts.
+type backoff struct {
+	attempt int
+	max     time.Duration
+}
+
+func newBackoff(max time.Duration) *backoff {
+	return &backoff{max: max}
+}
+
+func (b *backoff) next() time.Duration {
+	d := time.Duration(500*(1<<uint(b.attempt))) * time.Millisecond
+	if d <= 0 || d > b.max {
+		d = b.max
+	} else {
+		// Only grow attempt while it's still producing a shorter delay
+		// than max; once capped, leave it be so the exponent can't keep
+		// climbing until the shift overflows time.Duration and wraps
+		// negative, which would turn "capped backoff" into a busy-loop.
+		b.attempt++
+	}
+	return d
+}
+
+func (b *backoff) reset() {
+	b.attempt = 0
+}